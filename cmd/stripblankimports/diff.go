@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const diffContext = 3
+
+type diffOp struct {
+	kind byte // ' ', '-', or '+'
+	line string
+}
+
+// unifiedDiff returns a unified diff between a and b, with aPath/bPath used
+// as the "---"/"+++" file headers. It returns nil if a and b are identical.
+func unifiedDiff(aPath, bPath string, a, b []byte) []byte {
+	if bytes.Equal(a, b) {
+		return nil
+	}
+
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "--- %s\n", aPath)
+	fmt.Fprintf(buf, "+++ %s\n", bPath)
+	for _, hunk := range hunks(ops) {
+		hunk.writeTo(buf)
+	}
+	return buf.Bytes()
+}
+
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if last := lines[len(lines)-1]; last == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes a line-level edit script turning a into b, using the
+// standard LCS-table approach. It's O(len(a)*len(b)), which is fine for the
+// source files this tool operates on.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+type diffHunk struct {
+	aStart, aLen int
+	bStart, bLen int
+	ops          []diffOp
+}
+
+func (h diffHunk) writeTo(buf *bytes.Buffer) {
+	fmt.Fprintf(buf, "@@ -%d,%d +%d,%d @@\n", h.aStart, h.aLen, h.bStart, h.bLen)
+	for _, op := range h.ops {
+		buf.WriteByte(op.kind)
+		buf.WriteString(op.line)
+		if !strings.HasSuffix(op.line, "\n") {
+			buf.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunks groups a full edit script into unified-diff hunks, keeping
+// diffContext lines of unchanged context around each run of changes.
+func hunks(ops []diffOp) []diffHunk {
+	// First, find the [start, end) ranges of each run of changes, padded by
+	// diffContext lines of context on either side and merged if they'd
+	// otherwise overlap.
+	var ranges [][2]int
+	i := 0
+	for i < len(ops) {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		runStart := i
+		for i < len(ops) && ops[i].kind != ' ' {
+			i++
+		}
+		runEnd := i
+
+		start := runStart - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := runEnd + diffContext
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		if len(ranges) > 0 && start <= ranges[len(ranges)-1][1] {
+			ranges[len(ranges)-1][1] = end
+		} else {
+			ranges = append(ranges, [2]int{start, end})
+		}
+	}
+
+	// Track the source/destination line number each op index corresponds to.
+	aLineAt := make([]int, len(ops)+1)
+	bLineAt := make([]int, len(ops)+1)
+	aLineAt[0], bLineAt[0] = 1, 1
+	for idx, op := range ops {
+		aLineAt[idx+1], bLineAt[idx+1] = aLineAt[idx], bLineAt[idx]
+		switch op.kind {
+		case ' ':
+			aLineAt[idx+1]++
+			bLineAt[idx+1]++
+		case '-':
+			aLineAt[idx+1]++
+		case '+':
+			bLineAt[idx+1]++
+		}
+	}
+
+	var out []diffHunk
+	for _, r := range ranges {
+		start, end := r[0], r[1]
+		hunkOps := ops[start:end]
+		aLen, bLen := 0, 0
+		for _, op := range hunkOps {
+			switch op.kind {
+			case ' ':
+				aLen++
+				bLen++
+			case '-':
+				aLen++
+			case '+':
+				bLen++
+			}
+		}
+		out = append(out, diffHunk{
+			aStart: aLineAt[start],
+			aLen:   aLen,
+			bStart: bLineAt[start],
+			bLen:   bLen,
+			ops:    hunkOps,
+		})
+	}
+
+	return out
+}
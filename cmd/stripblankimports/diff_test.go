@@ -0,0 +1,85 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiffNoChange(t *testing.T) {
+	content := []byte("package foo\n")
+	if diff := unifiedDiff("a/foo.go", "b/foo.go", content, content); diff != nil {
+		t.Errorf("expected nil diff for identical content, got:\n%s", diff)
+	}
+}
+
+func TestUnifiedDiffSingleHunk(t *testing.T) {
+	a := []byte("package foo\n\nfunc F() {\n\told()\n}\n")
+	b := []byte("package foo\n\nfunc F() {\n\tnew()\n}\n")
+
+	diff := unifiedDiff("a/foo.go", "b/foo.go", a, b)
+	want := "--- a/foo.go\n" +
+		"+++ b/foo.go\n" +
+		"@@ -1,5 +1,5 @@\n" +
+		" package foo\n" +
+		" \n" +
+		" func F() {\n" +
+		"-\told()\n" +
+		"+\tnew()\n" +
+		" }\n"
+	if string(diff) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", diff, want)
+	}
+}
+
+// TestUnifiedDiffMultipleHunks checks that two change runs far enough apart
+// that their padded context ranges don't overlap produce two separate @@
+// hunks rather than one merged one.
+func TestUnifiedDiffMultipleHunks(t *testing.T) {
+	aLines := make([]string, 0, 20)
+	bLines := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		switch i {
+		case 2:
+			aLines = append(aLines, "old near top")
+			bLines = append(bLines, "new near top")
+		case 17:
+			aLines = append(aLines, "old near bottom")
+			bLines = append(bLines, "new near bottom")
+		default:
+			aLines = append(aLines, "same")
+			bLines = append(bLines, "same")
+		}
+	}
+	a := []byte(strings.Join(aLines, "\n") + "\n")
+	b := []byte(strings.Join(bLines, "\n") + "\n")
+
+	diff := unifiedDiff("a/foo.go", "b/foo.go", a, b)
+	if got := strings.Count(string(diff), "@@"); got != 4 {
+		t.Errorf("expected 2 hunks (4 \"@@\" markers), got %d in:\n%s", got/2, diff)
+	}
+}
+
+// TestUnifiedDiffMergesOverlappingContext checks that two change runs close
+// enough together that their padded context ranges overlap are merged into
+// a single hunk instead of two.
+func TestUnifiedDiffMergesOverlappingContext(t *testing.T) {
+	aLines := []string{"a", "b", "old1", "c", "d", "old2", "e", "f"}
+	bLines := []string{"a", "b", "new1", "c", "d", "new2", "e", "f"}
+	a := []byte(strings.Join(aLines, "\n") + "\n")
+	b := []byte(strings.Join(bLines, "\n") + "\n")
+
+	diff := unifiedDiff("a/foo.go", "b/foo.go", a, b)
+	if got := strings.Count(string(diff), "@@"); got != 2 {
+		t.Errorf("expected a single merged hunk (2 \"@@\" markers), got %d in:\n%s", got/2, diff)
+	}
+}
+
+func TestUnifiedDiffNoTrailingNewline(t *testing.T) {
+	a := []byte("package foo\n")
+	b := []byte("package foo\nextra")
+
+	diff := unifiedDiff("a/foo.go", "b/foo.go", a, b)
+	if !strings.Contains(string(diff), "\\ No newline at end of file") {
+		t.Errorf("expected a \"no newline\" marker, got:\n%s", diff)
+	}
+}
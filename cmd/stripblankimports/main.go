@@ -0,0 +1,320 @@
+// Command stripblankimports strips blank lines out of factored import
+// blocks. It's a thin CLI wrapper around the stripblankimports package --
+// see that package's godoc for the library API.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+
+	"golang.org/x/tools/imports"
+
+	"github.com/rpflynn22/stripblankimports"
+)
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "stripblankimports [flags] path [path...]\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "path may be a file, a directory, or a \"./...\" pattern\n")
+	flag.PrintDefaults()
+}
+
+// For writeback:
+//		For each file:
+//			read file
+//			stripblanks transform
+//			write file
+//		Goimports batch run with writeback
+//
+// For nonwriteback:
+//		For each file:
+//			read file
+//			stripblanks transform
+//			pass to goimports
+//			Run goimports with stdin as output of previous transform
+//
+
+func main() {
+	flag.Usage = usage
+	local := flag.String("local", "", "local grouping flag to goimports")
+	verbose := flag.Bool("v", false, "verbose logging")
+	writeBack := flag.Bool("w", false, "write back to file")
+	diffMode := flag.Bool("d", false, "print a unified diff instead of writing or printing the result; exits non-zero if any file would change")
+	goimportsPath := flag.String("p", "goimports", "path to goimports executable, used with -exec-goimports")
+	execGoimports := flag.Bool("exec-goimports", false, "shell out to a goimports executable instead of running golang.org/x/tools/imports in-process")
+	prune := flag.Bool("prune", false, "remove unused imports before squashing blank lines")
+	fold := flag.Bool("fold", false, "merge unfactored import lines into a factored block")
+	skip := flag.String("skip", "", "regexp of file paths to exclude when a path argument is a directory or \"./...\" pattern")
+	jobs := flag.Int("j", runtime.GOMAXPROCS(0), "number of files to process concurrently")
+	flag.Parse()
+
+	var skipRe *regexp.Regexp
+	if *skip != "" {
+		re, err := regexp.Compile(*skip)
+		if err != nil {
+			log.Fatalf("invalid -skip pattern: %s", err)
+		}
+		skipRe = re
+	}
+
+	filenames, err := expandArgs(flag.Args(), skipRe)
+	if err != nil {
+		log.Fatalf("resolving paths: %s", err)
+	}
+
+	opts := stripblankimports.Options{
+		Comments:  true,
+		TabIndent: true,
+		Prune:     *prune,
+		Fold:      *fold,
+	}
+
+	format := func(content []byte) ([]byte, error) {
+		return stripblankimports.Format(content, opts)
+	}
+	xform := stitchXform(format, goImportsStdIO(*local, *execGoimports, *goimportsPath))
+
+	switch {
+	case *diffMode:
+		if diffDriver(filenames, xform, *jobs, *verbose) {
+			os.Exit(1)
+		}
+	case *writeBack:
+		writeBackDriver(filenames, format, *local, *execGoimports, *goimportsPath, *jobs, *verbose)
+	default:
+		stdOutDriver(filenames, xform, *jobs, *verbose)
+	}
+}
+
+type xformFn func([]byte) ([]byte, error)
+
+func stitchXform(fns ...xformFn) xformFn {
+	return func(content []byte) ([]byte, error) {
+		for _, fn := range fns {
+			contentOut, err := fn(content)
+			if err != nil {
+				return content, err
+			}
+			content = contentOut
+		}
+		return content, nil
+	}
+}
+
+// readAndXform reads filename and runs it through xform, logging (when
+// verbose) either failure.
+func readAndXform(filename string, xform xformFn, verbose bool) ([]byte, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		if verbose {
+			log.Printf("error reading file %s: %s", filename, err)
+		}
+		return nil, err
+	}
+
+	contentOut, err := xform(content)
+	if err != nil {
+		if verbose {
+			log.Printf("error processing file %s: %s", filename, err)
+		}
+		if contentOut == nil {
+			return nil, err
+		}
+	}
+	return contentOut, nil
+}
+
+// stdOutDriver runs xform over filenames using a pool of jobs workers, then
+// writes each file's result to stdout in argv order so results never
+// interleave.
+func stdOutDriver(filenames []string, xform xformFn, jobs int, verbose bool) {
+	contents, _ := perFile(filenames, jobs, func(filename string) ([]byte, error) {
+		return readAndXform(filename, xform, verbose)
+	})
+
+	for _, content := range contents {
+		if content == nil {
+			continue
+		}
+		fmt.Fprint(os.Stdout, string(content))
+	}
+}
+
+// diffDriver prints a unified diff of what xform would change in each file,
+// without writing anything back. It reports whether any file would change,
+// so the caller can set a non-zero exit code for use in CI. Files are
+// processed by a pool of jobs workers, but diffs print in argv order.
+func diffDriver(filenames []string, xform xformFn, jobs int, verbose bool) (changed bool) {
+	contentsOut, _ := perFile(filenames, jobs, func(filename string) ([]byte, error) {
+		return readAndXform(filename, xform, verbose)
+	})
+
+	for i, filename := range filenames {
+		if contentsOut[i] == nil {
+			continue
+		}
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			continue
+		}
+
+		diff := unifiedDiff("a/"+filename, "b/"+filename, content, contentsOut[i])
+		if diff == nil {
+			continue
+		}
+		changed = true
+		os.Stdout.Write(diff)
+	}
+	return changed
+}
+
+func writeBackDriver(filenames []string, xform xformFn, local string, execGoimports bool, goimportsPath string, jobs int, verbose bool) {
+	_, errs := perFile(filenames, jobs, func(filename string) ([]byte, error) {
+		contentOut, err := readAndXform(filename, xform, verbose)
+		if contentOut == nil {
+			return nil, err
+		}
+		if writeErr := os.WriteFile(filename, contentOut, 0644 /* this shouldn't have effect, since the file exists */); writeErr != nil {
+			return nil, writeErr
+		}
+		return nil, nil
+	})
+	if verbose {
+		for i, err := range errs {
+			if err != nil {
+				log.Printf("error writing file %s: %s", filenames[i], err)
+			}
+		}
+	}
+
+	if err := goImportsWriteBack(local, execGoimports, goimportsPath, jobs, filenames...); err != nil && verbose {
+		log.Printf("goimports error: %s", err)
+	}
+}
+
+// Read the file, do the formatting, and truncate and recreate it with the new
+// content.
+func fileIO(filename string, xform func([]byte) ([]byte, error)) error {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	out, err := xform(content)
+	if err != nil {
+		return fmt.Errorf("formatting: %w", err)
+		// Todo: log
+	}
+
+	fw, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("truncate file: %w", err)
+	}
+	defer fw.Close()
+
+	if _, err := fw.Write(out); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	return nil
+}
+
+// goImportsOptions are the import-adding/removing options applied on top of
+// whatever stripblankimports.Format already did. Comments/TabIndent/TabWidth
+// match imports.Process's own zero-value defaults.
+var goImportsOptions = &imports.Options{
+	Comments:  true,
+	TabIndent: true,
+	TabWidth:  8,
+}
+
+// goImportsWriteBack runs goimports over the provided filenames, writing the
+// result back to each file. The local flag corresponds to goimports' -local
+// flag; it's okay for it to be empty. Files are processed by a pool of jobs
+// workers.
+//
+// By default this runs golang.org/x/tools/imports in-process, the same code
+// goimports itself is built on. Set execGoimports to shell out to a real
+// goimports binary instead (as a single batched invocation), for callers who
+// don't want the tools dependency.
+func goImportsWriteBack(local string, execGoimports bool, goimportsPath string, jobs int, fname ...string) error {
+	if execGoimports {
+		return goImportsExecWriteBack(local, goimportsPath, fname...)
+	}
+
+	imports.LocalPrefix = local
+	_, errs := perFile(fname, jobs, func(filename string) ([]byte, error) {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("goimports: read %s: %w", filename, err)
+		}
+		out, err := imports.Process(filename, content, goImportsOptions)
+		if err != nil {
+			return nil, fmt.Errorf("goimports: %s: %w", filename, err)
+		}
+		if err := os.WriteFile(filename, out, 0644); err != nil {
+			return nil, fmt.Errorf("goimports: write %s: %w", filename, err)
+		}
+		return nil, nil
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func goImportsStdIO(local string, execGoimports bool, goimportsPath string) xformFn {
+	if execGoimports {
+		return goImportsExecStdIO(local, goimportsPath)
+	}
+
+	// imports.LocalPrefix is a package-level var, so it must be set once up
+	// front rather than inside the closure below -- that closure runs
+	// concurrently across perFile's worker pool, and assigning it per-call
+	// would be a data race.
+	imports.LocalPrefix = local
+
+	return func(content []byte) ([]byte, error) {
+		out, err := imports.Process("", content, goImportsOptions)
+		if err != nil {
+			return nil, fmt.Errorf("goimports: %w", err)
+		}
+		return out, nil
+	}
+}
+
+// goImportsExecWriteBack is the pre-in-process fallback: it shells out to a
+// goimports executable instead of linking golang.org/x/tools/imports in.
+func goImportsExecWriteBack(local, goimportsPath string, fname ...string) error {
+	cmd := exec.Command(
+		goimportsPath,
+		append([]string{"-local", local, "-w"}, fname...)...,
+	)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("goimports: %w", err)
+	}
+	return nil
+}
+
+func goImportsExecStdIO(local, goimportsPath string) xformFn {
+	return func(content []byte) ([]byte, error) {
+		cmd := exec.Command(
+			goimportsPath,
+			[]string{"-local", local}...,
+		)
+		cmd.Stdin = bytes.NewReader(content)
+		out := bytes.Buffer{}
+		cmd.Stdout = &out
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("goimports: %w", err)
+		}
+		return out.Bytes(), nil
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import "sync"
+
+// perFile runs fn over filenames using a pool of jobs workers, and returns
+// the per-file results and errors in the same order as filenames -- not the
+// order fn happened to finish in. A jobs value less than 1 is treated as 1.
+func perFile(filenames []string, jobs int, fn func(filename string) ([]byte, error)) (contents [][]byte, errs []error) {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	type indexed struct {
+		i int
+		f string
+	}
+
+	work := make(chan indexed)
+	contents = make([][]byte, len(filenames))
+	errs = make([]error, len(filenames))
+
+	var wg sync.WaitGroup
+	for w := 0; w < jobs && w < len(filenames); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range work {
+				contents[item.i], errs[item.i] = fn(item.f)
+			}
+		}()
+	}
+
+	go func() {
+		for i, f := range filenames {
+			work <- indexed{i, f}
+		}
+		close(work)
+	}()
+	wg.Wait()
+
+	return contents, errs
+}
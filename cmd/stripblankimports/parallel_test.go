@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestPerFileOrdering checks that results come back indexed by argv
+// position, not by the order workers happened to finish in. It deliberately
+// makes earlier filenames sleep longer than later ones, so a naive
+// append-as-you-go implementation would shuffle the output.
+func TestPerFileOrdering(t *testing.T) {
+	n := 50
+	filenames := make([]string, n)
+	for i := range filenames {
+		filenames[i] = fmt.Sprintf("file%d", i)
+	}
+
+	contents, errs := perFile(filenames, 8, func(filename string) ([]byte, error) {
+		// Sleep longer for earlier files, so a naive append-as-you-go
+		// implementation would return them out of order.
+		i := 0
+		fmt.Sscanf(filename, "file%d", &i)
+		time.Sleep(time.Duration(n-i) * time.Millisecond)
+		return []byte(filename), nil
+	})
+
+	for i, filename := range filenames {
+		if errs[i] != nil {
+			t.Fatalf("unexpected error for %s: %s", filename, errs[i])
+		}
+		if string(contents[i]) != filename {
+			t.Errorf("index %d: got %q, want %q", i, contents[i], filename)
+		}
+	}
+}
+
+// TestPerFileConcurrency checks that perFile actually runs fn concurrently
+// up to the jobs limit, rather than serially.
+func TestPerFileConcurrency(t *testing.T) {
+	n := 20
+	jobs := 4
+	filenames := make([]string, n)
+	for i := range filenames {
+		filenames[i] = fmt.Sprintf("file%d", i)
+	}
+
+	var current, max int64
+	perFile(filenames, jobs, func(filename string) ([]byte, error) {
+		n := atomic.AddInt64(&current, 1)
+		for {
+			m := atomic.LoadInt64(&max)
+			if n <= m || atomic.CompareAndSwapInt64(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt64(&current, -1)
+		return nil, nil
+	})
+
+	if max < 2 {
+		t.Errorf("expected some concurrent execution, max concurrent was %d", max)
+	}
+	if max > int64(jobs) {
+		t.Errorf("exceeded jobs limit: max concurrent was %d, jobs was %d", max, jobs)
+	}
+}
+
+// TestPerFileErrors checks that errors, like results, are indexed by argv
+// position.
+func TestPerFileErrors(t *testing.T) {
+	filenames := []string{"ok1", "bad", "ok2"}
+	_, errs := perFile(filenames, 2, func(filename string) ([]byte, error) {
+		if filename == "bad" {
+			return nil, fmt.Errorf("boom")
+		}
+		return []byte(filename), nil
+	})
+
+	if errs[0] != nil || errs[2] != nil {
+		t.Errorf("expected no error for ok1/ok2, got %v / %v", errs[0], errs[2])
+	}
+	if errs[1] == nil {
+		t.Error("expected an error for \"bad\"")
+	}
+}
+
+// TestPerFileJobsLessThanOne checks that a jobs value below 1 still makes
+// progress rather than deadlocking.
+func TestPerFileJobsLessThanOne(t *testing.T) {
+	filenames := []string{"a", "b", "c"}
+	contents, _ := perFile(filenames, 0, func(filename string) ([]byte, error) {
+		return []byte(filename), nil
+	})
+	for i, filename := range filenames {
+		if string(contents[i]) != filename {
+			t.Errorf("index %d: got %q, want %q", i, contents[i], filename)
+		}
+	}
+}
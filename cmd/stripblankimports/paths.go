@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"go/build"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// skipDirs are directories we never descend into when walking a tree,
+// regardless of -skip.
+var skipDirs = map[string]bool{
+	"vendor":   true,
+	"testdata": true,
+	".git":     true,
+}
+
+// expandArgs turns the path arguments on the command line into a flat,
+// deduplicated list of filenames. An argument may be a plain file, a
+// directory (walked recursively), or a "./..." style pattern (also walked
+// recursively, rooted at the path before "/..."). Of the files found by
+// walking, only .go files are kept, and those excluded by the current build
+// context (GOOS/GOARCH/build tags) or matching skip are left out. A plain
+// file argument is always kept, whatever its extension, build constraints
+// or match against skip.
+func expandArgs(args []string, skip *regexp.Regexp) ([]string, error) {
+	var filenames []string
+	seen := map[string]bool{}
+
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			filenames = append(filenames, path)
+		}
+	}
+
+	for _, arg := range args {
+		root := arg
+		recursive := true
+		if trimmed := strings.TrimSuffix(arg, "/..."); trimmed != arg {
+			root = trimmed
+			if root == "" {
+				root = "."
+			}
+		} else {
+			info, err := os.Stat(arg)
+			if err != nil {
+				return nil, fmt.Errorf("stat %s: %w", arg, err)
+			}
+			recursive = info.IsDir()
+			if !recursive {
+				add(arg)
+				continue
+			}
+		}
+
+		found, err := walkGoFiles(root, skip)
+		if err != nil {
+			return nil, fmt.Errorf("walk %s: %w", root, err)
+		}
+		for _, f := range found {
+			add(f)
+		}
+	}
+
+	return filenames, nil
+}
+
+// walkGoFiles recursively collects buildable .go files under root, skipping
+// vendor/testdata/.git and anything matching skip.
+func walkGoFiles(root string, skip *regexp.Regexp) ([]string, error) {
+	bctx := build.Default
+
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && skipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filepath.Ext(path) != ".go" {
+			return nil
+		}
+		if skip != nil && skip.MatchString(path) {
+			return nil
+		}
+
+		dir, name := filepath.Split(path)
+		ok, err := bctx.MatchFile(dir, name)
+		if err != nil || !ok {
+			return nil
+		}
+
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
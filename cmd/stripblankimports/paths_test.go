@@ -0,0 +1,159 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"testing"
+)
+
+// writeTree creates files (relative to a fresh temp dir) with empty content
+// and returns the temp dir root.
+func writeTree(t *testing.T, files ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, f := range files {
+		path := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("mkdir %s: %s", path, err)
+		}
+		if err := os.WriteFile(path, []byte("package foo\n"), 0644); err != nil {
+			t.Fatalf("write %s: %s", path, err)
+		}
+	}
+	return root
+}
+
+func TestWalkGoFiles(t *testing.T) {
+	root := writeTree(t,
+		"a.go",
+		"b.go",
+		"not_go.txt",
+		"sub/c.go",
+		"vendor/d.go",
+		"testdata/e.go",
+		".git/f.go",
+	)
+
+	got, err := walkGoFiles(root, nil)
+	if err != nil {
+		t.Fatalf("walkGoFiles: %s", err)
+	}
+	sort.Strings(got)
+
+	want := []string{
+		filepath.Join(root, "a.go"),
+		filepath.Join(root, "b.go"),
+		filepath.Join(root, "sub/c.go"),
+	}
+	sort.Strings(want)
+
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("got %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+// TestWalkGoFilesBuildTags checks that a file whose //go:build constraint
+// can't be satisfied by the current build context is excluded, while an
+// unconstrained file alongside it is kept.
+func TestWalkGoFilesBuildTags(t *testing.T) {
+	root := t.TempDir()
+
+	plain := filepath.Join(root, "plain.go")
+	if err := os.WriteFile(plain, []byte("package foo\n"), 0644); err != nil {
+		t.Fatalf("write %s: %s", plain, err)
+	}
+
+	excluded := filepath.Join(root, "excluded.go")
+	excludedContent := "//go:build never_defined_tag\n\npackage foo\n"
+	if err := os.WriteFile(excluded, []byte(excludedContent), 0644); err != nil {
+		t.Fatalf("write %s: %s", excluded, err)
+	}
+
+	got, err := walkGoFiles(root, nil)
+	if err != nil {
+		t.Fatalf("walkGoFiles: %s", err)
+	}
+
+	if len(got) != 1 || got[0] != plain {
+		t.Errorf("got %v, want only %s", got, plain)
+	}
+}
+
+func TestWalkGoFilesSkip(t *testing.T) {
+	root := writeTree(t, "keep.go", "skip_me.go")
+
+	skip := regexp.MustCompile(`skip_me\.go$`)
+	got, err := walkGoFiles(root, skip)
+	if err != nil {
+		t.Fatalf("walkGoFiles: %s", err)
+	}
+
+	if len(got) != 1 || got[0] != filepath.Join(root, "keep.go") {
+		t.Errorf("got %v, want only keep.go", got)
+	}
+}
+
+func TestExpandArgsFile(t *testing.T) {
+	root := writeTree(t, "a.go")
+	file := filepath.Join(root, "a.go")
+
+	got, err := expandArgs([]string{file}, nil)
+	if err != nil {
+		t.Fatalf("expandArgs: %s", err)
+	}
+	if len(got) != 1 || got[0] != file {
+		t.Errorf("got %v, want [%s]", got, file)
+	}
+}
+
+func TestExpandArgsDirectory(t *testing.T) {
+	root := writeTree(t, "a.go", "sub/b.go")
+
+	got, err := expandArgs([]string{root}, nil)
+	if err != nil {
+		t.Fatalf("expandArgs: %s", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %v, want 2 files", got)
+	}
+}
+
+func TestExpandArgsEllipsis(t *testing.T) {
+	root := writeTree(t, "a.go", "sub/b.go")
+
+	got, err := expandArgs([]string{root + "/..."}, nil)
+	if err != nil {
+		t.Fatalf("expandArgs: %s", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %v, want 2 files", got)
+	}
+}
+
+func TestExpandArgsDedup(t *testing.T) {
+	root := writeTree(t, "a.go")
+	file := filepath.Join(root, "a.go")
+
+	got, err := expandArgs([]string{file, root}, nil)
+	if err != nil {
+		t.Fatalf("expandArgs: %s", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("got %v, want a single deduplicated entry", got)
+	}
+}
+
+func TestExpandArgsMissingFile(t *testing.T) {
+	root := t.TempDir()
+	if _, err := expandArgs([]string{filepath.Join(root, "missing.go")}, nil); err == nil {
+		t.Error("expected an error for a nonexistent path")
+	}
+}
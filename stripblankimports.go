@@ -0,0 +1,263 @@
+// Package stripblankimports removes blank lines from inside factored import
+// blocks, so that tools which like to leave a blank line between import
+// groups (goimports with -local, editors, generators) don't leave the block
+// looking sparse.
+package stripblankimports
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strconv"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// Options controls how Format parses and re-prints a file.
+type Options struct {
+	Comments  bool // Print comments (comments are dropped when false)
+	TabIndent bool // Indent with tabs and align with spaces (tabs for both when false)
+	TabWidth  int  // Tab width (8 if the zero value is used)
+	AllErrors bool // Report all parse errors (not just the first 10)
+
+	Prune bool // Remove imports that aren't referenced anywhere in the file
+	Fold  bool // Merge unfactored "import \"x\"" lines into a factored block
+}
+
+// Format takes in a file's content and returns the same file's content with
+// the blank lines in import blocks removed. Returns an error when it can't
+// handle something -- to be logged & handled by calling code.
+func Format(content []byte, opts Options) ([]byte, error) {
+	mode := parser.ParseComments
+	if opts.AllErrors {
+		mode |= parser.AllErrors
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Prune {
+		pruneUnusedImports(fset, file)
+	}
+
+	if !opts.Prune && len(file.Imports) <= 1 {
+		// No point -- but when pruning, a file can legitimately go from
+		// several imports down to one (or zero), and we still want to print
+		// the pruned result rather than bail out.
+		return nil, fmt.Errorf("doesn't contain multiple imports")
+	}
+
+	if opts.Fold {
+		foldUnfactoredImports(fset, file)
+	}
+
+	// Squash each import decl independently, using its own Lparen/Rparen as
+	// bounds. Unfactored "import \"x\"" decls (no Lparen, one spec) are left
+	// untouched -- there's nothing to squash.
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			continue
+		}
+		if !gd.Lparen.IsValid() && len(gd.Specs) <= 1 {
+			continue
+		}
+
+		specs := make([]*ast.ImportSpec, len(gd.Specs))
+		for i, s := range gd.Specs {
+			specs[i] = s.(*ast.ImportSpec)
+		}
+
+		start, end := gd.Lparen, gd.Rparen
+		if !start.IsValid() {
+			start, end = specs[0].Pos(), specs[len(specs)-1].End()
+		}
+		squashBlankImportLines(fset, start, end, specs, file.Comments)
+	}
+
+	if !opts.Comments {
+		file.Comments = nil
+	}
+
+	printerCfg := printer.Config{Tabwidth: 8}
+	if opts.TabWidth > 0 {
+		printerCfg.Tabwidth = opts.TabWidth
+	}
+	if opts.TabIndent {
+		printerCfg.Mode |= printer.UseSpaces | printer.TabIndent
+	}
+
+	buf := bytes.Buffer{}
+	if err := printerCfg.Fprint(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pruneUnusedImports deletes any import spec that isn't referenced anywhere
+// in file, leaving blank (_) and dot (.) imports alone since those are
+// brought in for their side effects rather than their names.
+func pruneUnusedImports(fset *token.FileSet, file *ast.File) {
+	for _, group := range astutil.Imports(fset, file) {
+		for _, imp := range group {
+			if imp.Name != nil && (imp.Name.Name == "_" || imp.Name.Name == ".") {
+				continue
+			}
+
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+
+			if astutil.UsesImport(file, path) {
+				continue
+			}
+
+			if imp.Name != nil {
+				astutil.DeleteNamedImport(fset, file, imp.Name.Name, path)
+			} else {
+				astutil.DeleteImport(fset, file, path)
+			}
+		}
+	}
+
+	ast.SortImports(fset, file)
+}
+
+// foldUnfactoredImports merges lone "import \"x\"" decls into a factored
+// import block, via the same astutil machinery goimports itself uses to
+// add/remove imports. astutil.AddNamedImport picks the decl each import
+// joins by best-matching import path, not by position; a file with only
+// lone decls ends up with them folded into one block. A decl that's already
+// part of a factored block, or that has more than one spec, is left alone.
+func foldUnfactoredImports(fset *token.FileSet, file *ast.File) {
+	var toFold []*ast.ImportSpec
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			continue
+		}
+		if gd.Lparen.IsValid() || len(gd.Specs) != 1 {
+			continue
+		}
+		toFold = append(toFold, gd.Specs[0].(*ast.ImportSpec))
+	}
+
+	for _, imp := range toFold {
+		name := ""
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		astutil.DeleteNamedImport(fset, file, name, path)
+		astutil.AddNamedImport(fset, file, name, path)
+	}
+
+	ast.SortImports(fset, file)
+}
+
+// Let's reslice the comment slice so we only handle the relevant ones.
+func resliceComments(posStart, posEnd token.Pos, cm []*ast.CommentGroup) []*ast.CommentGroup {
+	cmLower, cmUpper := -1, -1
+	for i := 0; i < len(cm); i++ {
+		if cmLower == -1 && cm[i].Pos() > posStart && cm[i].Pos() < posEnd {
+			cmLower = i
+		}
+
+		if cmLower != -1 && cm[i].Pos() < posEnd {
+			cmUpper = i
+		}
+
+		if cm[i].Pos() > posEnd {
+			break
+		}
+	}
+
+	if cmLower == -1 {
+		// There's no comments in the import block -- return empty comment slice
+		return nil
+	}
+
+	return cm[cmLower : cmUpper+1]
+}
+
+func squashBlankImportLines(
+	fset *token.FileSet,
+	posStart, posEnd token.Pos,
+	imp []*ast.ImportSpec,
+	cm []*ast.CommentGroup,
+) {
+	if len(imp) < 2 {
+		// Why bother?
+		return
+	}
+
+	cm = resliceComments(posStart, posEnd, cm)
+
+	// Merge two sorted lists
+	impIdx, cmIdx := 0, 0
+	for impIdx < len(imp) || cmIdx < len(cm) {
+		curr := chooseNext(imp, cm, &impIdx, &cmIdx, true)
+		if impIdx == len(imp) && cmIdx == len(cm) {
+			// If we're at a point where the thing we're considering is the last
+			// thing (i.e. both pointers point to the end of their respective
+			// lists), we're done.
+			break
+		}
+
+		// Not incrementing here because we want the item we choose for next
+		// here to be curr in the next iteration.
+		next := chooseNext(imp, cm, &impIdx, &cmIdx, false)
+
+		// Take a couple steps to find the line number for the last line of curr
+		// and the first line of next. Note that both ImportSpecs and
+		// CommentGroups can be multiple lines.
+		currEnd, nextStart := curr.End(), next.Pos()
+		currFile, nextFile := fset.File(currEnd), fset.File(nextStart)
+		if currFile != nextFile {
+			panic("files unequal")
+		}
+		currEndLine, nextStartLine := currFile.Line(currEnd), currFile.Line(nextStart)
+
+		// For each additional line over the 1 line of difference allowed,
+		// sqwashit.
+		for i := 0; i < nextStartLine-currEndLine-1; i++ {
+			currFile.MergeLine(currEndLine)
+		}
+	}
+}
+
+// Pick the next element at the head of imp or cm based on whether either is
+// already exhausted or whose head element has an earlier Pos().
+//
+// If inc is set, increment the index pointer for the chosen slice.
+func chooseNext(imp []*ast.ImportSpec, cm []*ast.CommentGroup, impIdx, cmIdx *int, inc bool) ast.Node {
+	var out ast.Node
+	var incVar *int // Easy way to only check value of inc param once
+	if *impIdx >= len(imp) {
+		out = cm[*cmIdx]
+		incVar = cmIdx
+	} else if *cmIdx >= len(cm) {
+		out = imp[*impIdx]
+		incVar = impIdx
+	} else if imp[*impIdx].Pos() < cm[*cmIdx].Pos() {
+		out = imp[*impIdx]
+		incVar = impIdx
+	} else {
+		out = cm[*cmIdx]
+		incVar = cmIdx
+	}
+	if inc {
+		*incVar++
+	}
+	return out
+}
@@ -0,0 +1,137 @@
+package stripblankimports
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	inDir  = "./testdata/in"
+	outDir = "./testdata/expectedout"
+)
+
+func TestFormat(t *testing.T) {
+	testCases := []struct {
+		filename    string
+		errExpected bool
+	}{
+		{
+			// Multiple factored import decls: each is squashed on its own.
+			filename: "case1",
+		},
+		{
+			// Factored decl followed by an unfactored one: the unfactored
+			// import is left alone.
+			filename: "case3",
+		},
+		{
+			// Unfactored decl followed by a factored one.
+			filename: "case6",
+		},
+		{
+			// Unfactored, factored (with a comment), unfactored.
+			filename: "case7",
+		},
+		// This test fails -- would be nice to fix
+		//{
+		//	filename: "case8",
+		//},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.filename, func(t *testing.T) {
+			contentIn, err := os.ReadFile(filepath.Join("testdata", "in", testCase.filename))
+			if err != nil {
+				t.Fatalf("reading input fixture: %s", err)
+			}
+			actualOut, err := Format(contentIn, Options{Comments: true, TabIndent: true})
+			if testCase.errExpected {
+				if err == nil {
+					t.Errorf("expected error for file %s", testCase.filename)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				expContentOut, err := os.ReadFile(filepath.Join("testdata", "expectedout", testCase.filename))
+				if err != nil {
+					t.Fatalf("reading expected-output fixture: %s", err)
+				}
+				if !bytesEqual(expContentOut, actualOut) {
+					t.Errorf("expected:\n\n%s\n\ngot:\n\n%s", string(expContentOut), string(actualOut))
+				}
+			}
+		})
+	}
+}
+
+func TestFormatPrune(t *testing.T) {
+	testCases := []string{
+		"prune_named",
+		"prune_dot",
+		"prune_docref",
+		// Pruning down to a single remaining import must still print the
+		// pruned result instead of bailing out on "doesn't contain multiple
+		// imports".
+		"prune_two",
+	}
+
+	for _, filename := range testCases {
+		t.Run(filename, func(t *testing.T) {
+			fIn, _ := os.Open(filepath.Join("testdata", "in", filename))
+			contentIn, _ := io.ReadAll(fIn)
+			actualOut, err := Format(contentIn, Options{Comments: true, TabIndent: true, Prune: true})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			expFOut, _ := os.Open(filepath.Join("testdata", "expectedout", filename))
+			expContentOut, _ := io.ReadAll(expFOut)
+			if !bytesEqual(expContentOut, actualOut) {
+				t.Errorf("expected:\n\n%s\n\ngot:\n\n%s", string(expContentOut), string(actualOut))
+			}
+		})
+	}
+}
+
+func TestFormatFold(t *testing.T) {
+	testCases := []string{
+		"fold_basic",
+		// A factored block plus an unfactored import that sorts in between
+		// the block's existing entries -- folding must re-sort afterward.
+		"fold_sort",
+	}
+
+	for _, filename := range testCases {
+		t.Run(filename, func(t *testing.T) {
+			fIn, _ := os.Open(filepath.Join("testdata", "in", filename))
+			contentIn, _ := io.ReadAll(fIn)
+			actualOut, err := Format(contentIn, Options{Comments: true, TabIndent: true, Fold: true})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			expFOut, _ := os.Open(filepath.Join("testdata", "expectedout", filename))
+			expContentOut, _ := io.ReadAll(expFOut)
+			if !bytesEqual(expContentOut, actualOut) {
+				t.Errorf("expected:\n\n%s\n\ngot:\n\n%s", string(expContentOut), string(actualOut))
+			}
+		})
+	}
+}
+
+func bytesEqual(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}